@@ -0,0 +1,68 @@
+// Package cache provides an on-disk cache for expensive QueryReleases
+// results, keyed by the inputs that can change its answer: the repository,
+// the commit it was computed at, and the query option.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Key identifies a single cacheable QueryReleases call. LastCommitSHA pins the
+// repository's HEAD commit and Option captures every flag that affects output.
+// Tags added or moved without a new HEAD commit do not change the key.
+type Key struct {
+	RepoURL       string
+	LastCommitSHA string
+	Option        string
+}
+
+func (k Key) hash() string {
+	sum := sha256.Sum256([]byte(k.RepoURL + "\x00" + k.LastCommitSHA + "\x00" + k.Option))
+	return hex.EncodeToString(sum[:])
+}
+
+// Cache stores arbitrary JSON-marshalable values under a directory on disk.
+type Cache struct {
+	dir string
+}
+
+func New(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("could not create cache dir %v: %w", dir, err)
+	}
+	return &Cache{dir: dir}, nil
+}
+
+func (c *Cache) path(key Key) string {
+	return filepath.Join(c.dir, key.hash()+".json")
+}
+
+// Get reads the cached value for key into out. The second return value is
+// false when there is no cache entry for key.
+func (c *Cache) Get(key Key, out any) (bool, error) {
+	data, err := os.ReadFile(c.path(key))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Set persists value under key, overwriting any existing entry.
+func (c *Cache) Set(key Key, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path(key), data, 0o644)
+}
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"testing"
+)
+
+func TestCacheGetSetRoundTrip(t *testing.T) {
+	c, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	key := Key{RepoURL: "https://example.com/repo", LastCommitSHA: "abc123", Option: "since=2024-01-01"}
+	type value struct {
+		Count int `json:"count"`
+	}
+
+	var miss value
+	if hit, err := c.Get(key, &miss); hit || err != nil {
+		t.Fatalf("Get() on empty cache = hit:%v err:%v, want hit:false err:nil", hit, err)
+	}
+
+	if err := c.Set(key, value{Count: 3}); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+
+	var out value
+	hit, err := c.Get(key, &out)
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	if !hit {
+		t.Fatal("Get() after Set() = hit:false, want hit:true")
+	}
+	if out.Count != 3 {
+		t.Errorf("Get() value = %+v, want Count:3", out)
+	}
+}
+
+func TestCacheGetMissForDifferentKey(t *testing.T) {
+	c, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if err := c.Set(Key{RepoURL: "a", LastCommitSHA: "1", Option: "x"}, "value"); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+
+	var out string
+	hit, err := c.Get(Key{RepoURL: "a", LastCommitSHA: "2", Option: "x"}, &out)
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	if hit {
+		t.Error("Get() with a different LastCommitSHA should miss")
+	}
+}
@@ -1,31 +1,90 @@
 package cli
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
 	"time"
 
-	"github.com/hmiyado/four-keys/internal/releases"
+	"github.com/hmiyado/four-keys/internal/core"
 	"github.com/urfave/cli/v2"
 )
 
+const (
+	FormatJson       = "json"
+	FormatTable      = "table"
+	FormatCSV        = "csv"
+	FormatPrometheus = "prometheus"
+)
+
 func DefaultApp() *cli.App {
 	return &cli.App{
-		Name:   "four-keys",
-		Usage:  "analyze four keys metrics",
-		Flags:  getCommandReleasesFlags(),
+		Name:  "four-keys",
+		Usage: "analyze four keys metrics",
+		Flags: append(getCommandReleasesFlags(),
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "output format. one of json, table, csv, prometheus",
+				Value: FormatJson,
+			},
+			&cli.StringFlag{
+				Name:  "bucket",
+				Usage: "when set, emit a time series bucketed by day, week, month or quarter instead of a single snapshot",
+			},
+			&cli.IntFlag{
+				Name:  "rolling",
+				Usage: "average each bucket over itself and the preceding N-1 buckets (requires --bucket)",
+				Value: 1,
+			},
+		),
 		Action: defaultAction,
 		Commands: []*cli.Command{
 			GetCommandReleases(),
+			GetCommandServe(),
 		},
 	}
 }
 
+// DurationWithTimeUnit is a time.Duration marshaled as its raw nanosecond
+// count, the wire format QueryReleases's JSON consumers expect.
+type DurationWithTimeUnit time.Duration
+
+func getDurationWithTimeUnit(d time.Duration) DurationWithTimeUnit {
+	return DurationWithTimeUnit(d)
+}
+
+// String renders d the way time.Duration does (e.g. "1h30m0s") for table/CSV
+// output; json.Marshal ignores this and still encodes the raw nanosecond count.
+func (d DurationWithTimeUnit) String() string {
+	return time.Duration(d).String()
+}
+
+type DurationPercentiles struct {
+	P50 DurationWithTimeUnit `json:"p50"`
+	P75 DurationWithTimeUnit `json:"p75"`
+	P90 DurationWithTimeUnit `json:"p90"`
+	P95 DurationWithTimeUnit `json:"p95"`
+}
+
+type DoraPerformanceLevels struct {
+	DeploymentFrequency   string `json:"deploymentFrequency"`
+	LeadTimeForChanges    string `json:"leadTimeForChanges"`
+	TimeToRestoreServices string `json:"timeToRestoreServices"`
+	ChangeFailureRate     string `json:"changeFailureRate"`
+}
+
 type DefaultCliOutput struct {
-	Option                *releases.Option     `json:"option"`
-	DeploymentFrequency   float64              `json:"deploymentFrequency"`
-	LeadTimeForChanges    DurationWithTimeUnit `json:"leadTimeForChanges"`
-	TimeToRestoreServices DurationWithTimeUnit `json:"timeToRestoreServices"`
-	ChangeFailureRate     float64              `json:"changeFailureRate"`
+	Option                         *core.Option          `json:"option"`
+	DeploymentFrequency            float64               `json:"deploymentFrequency"`
+	LeadTimeForChanges             DurationWithTimeUnit  `json:"leadTimeForChanges"`
+	LeadTimeForChangesPercentile   DurationPercentiles   `json:"leadTimeForChangesPercentile"`
+	TimeToRestoreServices          DurationWithTimeUnit  `json:"timeToRestoreServices"`
+	TimeToRestoreServicePercentile DurationPercentiles   `json:"timeToRestoreServicePercentile"`
+	ChangeFailureRate              float64               `json:"changeFailureRate"`
+	PerformanceLevels              DoraPerformanceLevels `json:"performanceLevels"`
 }
 
 func defaultAction(ctx *cli.Context) error {
@@ -41,28 +100,55 @@ func defaultAction(ctx *cli.Context) error {
 		return err
 	}
 
+	if ctx.String("bucket") != "" {
+		return bucketAction(ctx, context, releases, option)
+	}
+
 	duration := option.Until.Sub(option.Since)
 	daysCount := int(duration.Hours() / 24)
 	releasesCount := len(releases)
 	deploymentFrequency := float64(releasesCount) / float64(daysCount)
+	leadTimeForChanges := getMeanLeadTimeForChanges(releases)
+	timeToRestoreServices := getTimeToRestoreServices(releases)
+	changeFailureRate := getChangeFailureRate(releases)
 
-	outputJson, err := json.Marshal(&DefaultCliOutput{
-		Option:                option,
-		DeploymentFrequency:   deploymentFrequency,
-		LeadTimeForChanges:    getDurationWithTimeUnit(getMeanLeadTimeForChanges(releases)),
-		TimeToRestoreServices: getDurationWithTimeUnit(getTimeToRestoreServices(releases)),
-		ChangeFailureRate:     getChangeFailureRate(releases),
-	})
-	if err != nil {
-		context.Error(err)
-		return err
+	output := &DefaultCliOutput{
+		Option:                         option,
+		DeploymentFrequency:            deploymentFrequency,
+		LeadTimeForChanges:             getDurationWithTimeUnit(leadTimeForChanges),
+		LeadTimeForChangesPercentile:   getLeadTimeForChangesPercentiles(releases),
+		TimeToRestoreServices:          getDurationWithTimeUnit(timeToRestoreServices),
+		TimeToRestoreServicePercentile: getTimeToRestoreServicePercentiles(releases),
+		ChangeFailureRate:              changeFailureRate,
+		PerformanceLevels: DoraPerformanceLevels{
+			DeploymentFrequency:   getDeploymentFrequencyPerformanceLevel(deploymentFrequency),
+			LeadTimeForChanges:    getLeadTimeForChangesPerformanceLevel(leadTimeForChanges),
+			TimeToRestoreServices: getTimeToRestoreServicesPerformanceLevel(timeToRestoreServices),
+			ChangeFailureRate:     getChangeFailureRatePerformanceLevel(changeFailureRate),
+		},
+	}
+
+	format := ctx.String("format")
+	switch format {
+	case FormatTable:
+		writeTable(context, releases, output)
+	case FormatCSV:
+		writeCSV(context, releases, output)
+	case FormatPrometheus:
+		writePrometheus(context, output)
+	default:
+		outputJson, err := json.Marshal(output)
+		if err != nil {
+			context.Error(err)
+			return err
+		}
+		context.Write(outputJson)
 	}
-	context.Write(outputJson)
 	return nil
 
 }
 
-func getMeanLeadTimeForChanges(release []*releases.Release) time.Duration {
+func getMeanLeadTimeForChanges(release []*core.Release) time.Duration {
 	if len(release) == 0 {
 		return time.Duration(0)
 	}
@@ -73,7 +159,7 @@ func getMeanLeadTimeForChanges(release []*releases.Release) time.Duration {
 	return time.Duration(int64(sum) / int64(len(release)))
 }
 
-func getTimeToRestoreServices(releases []*releases.Release) time.Duration {
+func getTimeToRestoreServices(releases []*core.Release) time.Duration {
 	sum := time.Duration(0)
 	countOfRestoreService := 0
 	failedReleaseIndex := -1
@@ -98,7 +184,7 @@ func getTimeToRestoreServices(releases []*releases.Release) time.Duration {
 	return sum / time.Duration(countOfRestoreService)
 }
 
-func getChangeFailureRate(releases []*releases.Release) float64 {
+func getChangeFailureRate(releases []*core.Release) float64 {
 	if len(releases) == 0 {
 		return 0
 	}
@@ -111,3 +197,168 @@ func getChangeFailureRate(releases []*releases.Release) float64 {
 	}
 	return float64(sumOfFailure) / float64(len(releases))
 }
+
+// percentile returns the value at percentile p (0-1) of durations using
+// linear interpolation between the two nearest ranks (nearest-rank index p*(n-1)).
+func percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return time.Duration(0)
+	}
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+
+	rank := p * float64(len(sorted)-1)
+	lower := int(rank)
+	upper := lower + 1
+	if upper >= len(sorted) {
+		return sorted[lower]
+	}
+	fraction := rank - float64(lower)
+	lowerValue := float64(sorted[lower])
+	upperValue := float64(sorted[upper])
+	return time.Duration(lowerValue + fraction*(upperValue-lowerValue))
+}
+
+func getLeadTimeForChangesPercentiles(releases []*core.Release) DurationPercentiles {
+	durations := make([]time.Duration, 0, len(releases))
+	for _, release := range releases {
+		durations = append(durations, release.LeadTimeForChanges)
+	}
+	return DurationPercentiles{
+		P50: getDurationWithTimeUnit(percentile(durations, 0.5)),
+		P75: getDurationWithTimeUnit(percentile(durations, 0.75)),
+		P90: getDurationWithTimeUnit(percentile(durations, 0.9)),
+		P95: getDurationWithTimeUnit(percentile(durations, 0.95)),
+	}
+}
+
+func getTimeToRestoreServicePercentiles(releases []*core.Release) DurationPercentiles {
+	durations := make([]time.Duration, 0, len(releases))
+	for _, release := range releases {
+		if release.Result.TimeToRestore != nil {
+			durations = append(durations, *release.Result.TimeToRestore)
+		}
+	}
+	return DurationPercentiles{
+		P50: getDurationWithTimeUnit(percentile(durations, 0.5)),
+		P75: getDurationWithTimeUnit(percentile(durations, 0.75)),
+		P90: getDurationWithTimeUnit(percentile(durations, 0.9)),
+		P95: getDurationWithTimeUnit(percentile(durations, 0.95)),
+	}
+}
+
+// getDeploymentFrequencyPerformanceLevel classifies deploymentFrequency (releases/day)
+// using the DORA state-of-devops thresholds.
+func getDeploymentFrequencyPerformanceLevel(deploymentFrequency float64) string {
+	switch {
+	case deploymentFrequency >= 1:
+		return "Elite"
+	case deploymentFrequency >= 1.0/7:
+		return "High"
+	case deploymentFrequency >= 1.0/30:
+		return "Medium"
+	default:
+		return "Low"
+	}
+}
+
+func getLeadTimeForChangesPerformanceLevel(leadTimeForChanges time.Duration) string {
+	switch {
+	case leadTimeForChanges <= 24*time.Hour:
+		return "Elite"
+	case leadTimeForChanges <= 7*24*time.Hour:
+		return "High"
+	case leadTimeForChanges <= 30*24*time.Hour:
+		return "Medium"
+	default:
+		return "Low"
+	}
+}
+
+func getTimeToRestoreServicesPerformanceLevel(timeToRestoreServices time.Duration) string {
+	switch {
+	case timeToRestoreServices <= time.Hour:
+		return "Elite"
+	case timeToRestoreServices <= 24*time.Hour:
+		return "High"
+	case timeToRestoreServices <= 7*24*time.Hour:
+		return "Medium"
+	default:
+		return "Low"
+	}
+}
+
+func getChangeFailureRatePerformanceLevel(changeFailureRate float64) string {
+	switch {
+	case changeFailureRate <= 0.15:
+		return "Elite"
+	case changeFailureRate <= 0.3:
+		return "Medium"
+	default:
+		return "Low"
+	}
+}
+
+// outputWriter is the subset of CliContextWrapper that the format renderers
+// need, so the "serve" command's HTTP handlers can reuse them too.
+type outputWriter interface {
+	Write([]byte)
+	Error(error)
+}
+
+// writeTable renders a fixed-width columnar report, one row per release
+// (aligned with a tabwriter), followed by a blank line and the four keys
+// summary with their DORA performance levels.
+func writeTable(context outputWriter, allReleases []*core.Release, output *DefaultCliOutput) {
+	buf := &bytes.Buffer{}
+	tw := tabwriter.NewWriter(buf, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "REVISION\tUPDATED\tSTATUS\tLEAD TIME")
+	for _, release := range allReleases {
+		status := "success"
+		if !release.Result.IsSuccess {
+			status = "failed"
+		}
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\n", release.Tag, release.Date.Format(time.RFC3339), status, release.LeadTimeForChanges)
+	}
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "METRIC\tVALUE\tLEVEL")
+	fmt.Fprintf(tw, "DeploymentFrequency\t%.3f/day\t%v\n", output.DeploymentFrequency, output.PerformanceLevels.DeploymentFrequency)
+	fmt.Fprintf(tw, "LeadTimeForChanges\t%v\t%v\n", output.LeadTimeForChanges, output.PerformanceLevels.LeadTimeForChanges)
+	fmt.Fprintf(tw, "TimeToRestoreServices\t%v\t%v\n", output.TimeToRestoreServices, output.PerformanceLevels.TimeToRestoreServices)
+	fmt.Fprintf(tw, "ChangeFailureRate\t%.3f\t%v\n", output.ChangeFailureRate, output.PerformanceLevels.ChangeFailureRate)
+
+	tw.Flush()
+	context.Write(buf.Bytes())
+}
+
+func writeCSV(context outputWriter, allReleases []*core.Release, output *DefaultCliOutput) {
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, "tag,date,leadTimeForChanges,isSuccess")
+	for _, release := range allReleases {
+		fmt.Fprintf(buf, "%v,%v,%v,%v\n", release.Tag, release.Date.Format(time.RFC3339), release.LeadTimeForChanges, release.Result.IsSuccess)
+	}
+	fmt.Fprintf(buf, "summary,,%v,deploymentFrequency=%.3f changeFailureRate=%.3f\n", output.LeadTimeForChanges, output.DeploymentFrequency, output.ChangeFailureRate)
+	context.Write(buf.Bytes())
+}
+
+func writePrometheus(context outputWriter, output *DefaultCliOutput) {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "four_keys_deployment_frequency %v\n", output.DeploymentFrequency)
+	fmt.Fprintf(buf, "four_keys_change_failure_rate %v\n", output.ChangeFailureRate)
+	writePrometheusPercentiles(buf, "four_keys_lead_time_for_changes_seconds", output.LeadTimeForChangesPercentile)
+	writePrometheusPercentiles(buf, "four_keys_time_to_restore_services_seconds", output.TimeToRestoreServicePercentile)
+	context.Write(buf.Bytes())
+}
+
+func writePrometheusPercentiles(w io.Writer, name string, percentiles DurationPercentiles) {
+	fmt.Fprintf(w, "%v{quantile=\"0.5\"} %v\n", name, time.Duration(percentiles.P50).Seconds())
+	fmt.Fprintf(w, "%v{quantile=\"0.75\"} %v\n", name, time.Duration(percentiles.P75).Seconds())
+	fmt.Fprintf(w, "%v{quantile=\"0.9\"} %v\n", name, time.Duration(percentiles.P90).Seconds())
+	fmt.Fprintf(w, "%v{quantile=\"0.95\"} %v\n", name, time.Duration(percentiles.P95).Seconds())
+}
@@ -0,0 +1,312 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/hmiyado/four-keys/internal/core"
+	"github.com/urfave/cli/v2"
+)
+
+// getCommandReleasesFlags are the flags shared by the top-level app (the four
+// keys summary) and the "releases" command (the raw release list): they all
+// describe the same repository/Option, just rendered differently.
+func getCommandReleasesFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "repository",
+			Usage: "repository to analyze: a local path, or an https URL to clone in memory. Defaults to the current directory",
+		},
+		&cli.StringFlag{
+			Name:  "since",
+			Usage: "only include releases on or after this date (YYYY-MM-DD)",
+		},
+		&cli.StringFlag{
+			Name:  "until",
+			Usage: "only include releases before this date (YYYY-MM-DD); releases on the date itself are excluded",
+		},
+		&cli.StringFlag{
+			Name:  "ignore-pattern",
+			Usage: "regexp of tag names to exclude from the release list",
+		},
+		&cli.StringFlag{
+			Name:  "fix-commit-pattern",
+			Usage: `regexp identifying a fix commit; defaults to commit messages containing "hotfix"`,
+		},
+		&cli.IntFlag{
+			Name:  "concurrency",
+			Usage: "how many release sources to compute metrics for in parallel. 0 means runtime.NumCPU()",
+		},
+		&cli.StringFlag{
+			Name:  "source",
+			Usage: fmt.Sprintf("where releases come from: %v, %v, %v or %v", core.ReleaseSourceTags, core.ReleaseSourceGitHub, core.ReleaseSourceGitLab, core.ReleaseSourceChangelog),
+			Value: core.ReleaseSourceTags,
+		},
+		&cli.StringFlag{
+			Name:  "token",
+			Usage: "access token for --source github/gitlab",
+		},
+		&cli.StringFlag{
+			Name:  "changelog",
+			Usage: "path to CHANGELOG.md, used with --source changelog",
+			Value: "CHANGELOG.md",
+		},
+		&cli.StringFlag{
+			Name:  "incidents-source",
+			Usage: fmt.Sprintf("tie ChangeFailureRate/TimeToRestoreServices to real incidents in addition to the fix-commit heuristic: %v, %v or %v", core.IncidentSourceGitHub, core.IncidentSourceGitLab, core.IncidentSourceJira),
+		},
+		&cli.StringFlag{
+			Name:  "incidents-label",
+			Usage: "issue label identifying an incident, used with --incidents-source github/gitlab",
+		},
+		&cli.StringFlag{
+			Name:  "incidents-jql",
+			Usage: "JQL query identifying incidents, used with --incidents-source jira",
+		},
+		&cli.StringFlag{
+			Name:  "incidents-token",
+			Usage: "access token for --incidents-source github/gitlab/jira",
+		},
+		&cli.StringFlag{
+			Name:  "incidents-jira-base-url",
+			Usage: "Jira base URL, used with --incidents-source jira",
+		},
+		&cli.StringFlag{
+			Name:  "incidents-jira-email",
+			Usage: "Jira account email, accepted with --incidents-source jira; it is not sent to Jira, which is called with --incidents-token as a bearer token",
+		},
+	}
+}
+
+// GetCommandReleases returns the "releases" subcommand, which prints the raw
+// release list QueryReleases derives from the repository, without the four
+// keys summary metrics the top-level app also computes.
+func GetCommandReleases() *cli.Command {
+	return &cli.Command{
+		Name:   "releases",
+		Usage:  "list the releases four-keys derives from the repository",
+		Flags:  getCommandReleasesFlags(),
+		Action: releasesAction,
+	}
+}
+
+// ReleasesCliOutput is the "releases" command's JSON output.
+type ReleasesCliOutput struct {
+	Option   *core.Option    `json:"option"`
+	Releases []*core.Release `json:"releases"`
+}
+
+func releasesAction(ctx *cli.Context) error {
+	context := &CliContextWrapper{context: ctx}
+	releases, err := QueryReleases(context)
+	if err != nil {
+		context.Error(err)
+		return err
+	}
+	option, err := context.Option()
+	if err != nil {
+		context.Error(err)
+		return err
+	}
+
+	outputJson, err := json.Marshal(&ReleasesCliOutput{Option: option, Releases: releases})
+	if err != nil {
+		context.Error(err)
+		return err
+	}
+	context.Write(outputJson)
+	return nil
+}
+
+// CliContextWrapper adapts a *cli.Context into the Option/Repository it
+// describes, plus the Write/Error pair defaultAction, bucketAction and
+// releasesAction use to produce output, so all three commands interpret the
+// same flags identically.
+type CliContextWrapper struct {
+	context *cli.Context
+}
+
+func (c *CliContextWrapper) Write(data []byte) {
+	fmt.Fprintln(c.context.App.Writer, string(data))
+}
+
+func (c *CliContextWrapper) Error(err error) {
+	writer := c.context.App.ErrWriter
+	if writer == nil {
+		writer = c.context.App.Writer
+	}
+	fmt.Fprintln(writer, err.Error())
+}
+
+// Repository opens the repository --repository names: a local path (or the
+// current directory when omitted), or an https URL cloned in memory via
+// core.OpenRepository.
+func (c *CliContextWrapper) Repository() (*git.Repository, error) {
+	repository := c.context.String("repository")
+	if repository == "" {
+		localRepository, err := git.PlainOpen(".")
+		if err != nil {
+			return nil, fmt.Errorf("could not open local repository: %w", err)
+		}
+		return localRepository, nil
+	}
+	return core.OpenRepository(repository)
+}
+
+// Option builds the core.Option --since/--until/--ignore-pattern/--source etc.
+// describe.
+func (c *CliContextWrapper) Option() (*core.Option, error) {
+	since, err := parseDateFlag(c.context, "since", time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	until, err := parseDateFlag(c.context, "until", time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	ignorePattern, err := compilePatternFlag(c.context, "ignore-pattern")
+	if err != nil {
+		return nil, err
+	}
+	fixCommitPattern, err := compilePatternFlag(c.context, "fix-commit-pattern")
+	if err != nil {
+		return nil, err
+	}
+
+	sourceProvider, err := c.sourceProvider()
+	if err != nil {
+		return nil, err
+	}
+	incidentProvider, err := c.incidentProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &core.Option{
+		Since:             since,
+		Until:             until,
+		IgnorePattern:     ignorePattern,
+		FixCommitPattern:  fixCommitPattern,
+		IsLocalRepository: c.context.String("repository") == "",
+		SourceProvider:    sourceProvider,
+		IncidentProvider:  incidentProvider,
+		Concurrency:       c.context.Int("concurrency"),
+	}, nil
+}
+
+// sourceProvider builds the core.ReleaseSourceProvider --source names,
+// defaulting to the historical tag-based TagProvider.
+func (c *CliContextWrapper) sourceProvider() (core.ReleaseSourceProvider, error) {
+	switch source := c.context.String("source"); source {
+	case "", core.ReleaseSourceTags:
+		return &core.TagProvider{}, nil
+	case core.ReleaseSourceGitHub:
+		owner, repo, err := splitOwnerRepo(c.context.String("repository"))
+		if err != nil {
+			return nil, fmt.Errorf("--source github requires --repository owner/repo or a github.com URL: %w", err)
+		}
+		return &core.GitHubReleasesProvider{Owner: owner, Repo: repo, Token: c.context.String("token")}, nil
+	case core.ReleaseSourceGitLab:
+		return &core.GitLabReleasesProvider{ProjectID: c.context.String("repository"), Token: c.context.String("token")}, nil
+	case core.ReleaseSourceChangelog:
+		return &core.ChangelogProvider{Path: c.context.String("changelog")}, nil
+	default:
+		return nil, fmt.Errorf("unknown --source %q: must be one of %v, %v, %v, %v", source, core.ReleaseSourceTags, core.ReleaseSourceGitHub, core.ReleaseSourceGitLab, core.ReleaseSourceChangelog)
+	}
+}
+
+// incidentProvider builds the core.IncidentProvider --incidents-source names.
+// Unset (the default) leaves Option.IncidentProvider nil, so QueryReleases
+// keeps relying solely on the fix-commit heuristic.
+func (c *CliContextWrapper) incidentProvider() (core.IncidentProvider, error) {
+	switch source := c.context.String("incidents-source"); source {
+	case "":
+		return nil, nil
+	case core.IncidentSourceGitHub:
+		owner, repo, err := splitOwnerRepo(c.context.String("repository"))
+		if err != nil {
+			return nil, fmt.Errorf("--incidents-source github requires --repository owner/repo or a github.com URL: %w", err)
+		}
+		return &core.GitHubIncidentProvider{
+			Owner: owner,
+			Repo:  repo,
+			Label: c.context.String("incidents-label"),
+			Token: c.context.String("incidents-token"),
+		}, nil
+	case core.IncidentSourceGitLab:
+		return &core.GitLabIncidentProvider{
+			ProjectID: c.context.String("repository"),
+			Label:     c.context.String("incidents-label"),
+			Token:     c.context.String("incidents-token"),
+		}, nil
+	case core.IncidentSourceJira:
+		return &core.JiraIncidentProvider{
+			BaseURL: c.context.String("incidents-jira-base-url"),
+			JQL:     c.context.String("incidents-jql"),
+			Email:   c.context.String("incidents-jira-email"),
+			Token:   c.context.String("incidents-token"),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown --incidents-source %q: must be one of %v, %v, %v", source, core.IncidentSourceGitHub, core.IncidentSourceGitLab, core.IncidentSourceJira)
+	}
+}
+
+// splitOwnerRepo extracts "owner", "repo" from either a bare "owner/repo" or
+// an "https://github.com/owner/repo[.git]"/"git@github.com:owner/repo.git"
+// URL, for --source/--incidents-source github.
+func splitOwnerRepo(repository string) (owner string, repo string, err error) {
+	trimmed := strings.TrimSuffix(repository, ".git")
+	trimmed = strings.TrimPrefix(trimmed, "https://github.com/")
+	trimmed = strings.TrimPrefix(trimmed, "git@github.com:")
+	parts := strings.Split(trimmed, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("could not parse owner/repo from %q", repository)
+	}
+	return parts[0], parts[1], nil
+}
+
+func parseDateFlag(ctx *cli.Context, name string, defaultValue time.Time) (time.Time, error) {
+	raw := ctx.String(name)
+	if raw == "" {
+		return defaultValue, nil
+	}
+	value, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%v %q: %w", name, raw, err)
+	}
+	return value, nil
+}
+
+func compilePatternFlag(ctx *cli.Context, name string) (*regexp.Regexp, error) {
+	raw := ctx.String(name)
+	if raw == "" {
+		return nil, nil
+	}
+	pattern, err := regexp.Compile(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%v %q: %w", name, raw, err)
+	}
+	return pattern, nil
+}
+
+// QueryReleases opens the repository and Option described by context's flags
+// and delegates to core.QueryReleases. defaultAction, bucketAction (via
+// defaultAction) and releasesAction all call this, so --source and
+// --incidents-source behave identically whether you're asking for the four
+// keys summary or the raw release list.
+func QueryReleases(context *CliContextWrapper) ([]*core.Release, error) {
+	option, err := context.Option()
+	if err != nil {
+		return nil, err
+	}
+	repository, err := context.Repository()
+	if err != nil {
+		return nil, err
+	}
+	return core.QueryReleases(repository, option), nil
+}
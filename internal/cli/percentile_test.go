@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	durations := []time.Duration{
+		1 * time.Hour,
+		2 * time.Hour,
+		3 * time.Hour,
+		4 * time.Hour,
+	}
+
+	// nearest-rank index p*(n-1) with linear interpolation, e.g. p50 of
+	// [1,2,3,4]h sits at rank 0.5*3=1.5, halfway between index 1 (2h) and
+	// index 2 (3h).
+	if got, want := percentile(durations, 0.5), 2*time.Hour+30*time.Minute; got != want {
+		t.Errorf("p50 = %v, want %v", got, want)
+	}
+	if got, want := percentile(durations, 0), 1*time.Hour; got != want {
+		t.Errorf("p0 = %v, want %v", got, want)
+	}
+	if got, want := percentile(durations, 1), 4*time.Hour; got != want {
+		t.Errorf("p100 = %v, want %v", got, want)
+	}
+	if got := percentile(nil, 0.5); got != 0 {
+		t.Errorf("percentile of empty slice = %v, want 0", got)
+	}
+}
+
+func TestGetDeploymentFrequencyPerformanceLevel(t *testing.T) {
+	cases := []struct {
+		deploymentFrequency float64
+		want                string
+	}{
+		{2, "Elite"},
+		{1.0 / 7, "High"},
+		{1.0 / 30, "Medium"},
+		{1.0 / 365, "Low"},
+	}
+	for _, c := range cases {
+		if got := getDeploymentFrequencyPerformanceLevel(c.deploymentFrequency); got != c.want {
+			t.Errorf("getDeploymentFrequencyPerformanceLevel(%v) = %v, want %v", c.deploymentFrequency, got, c.want)
+		}
+	}
+}
+
+func TestGetChangeFailureRatePerformanceLevel(t *testing.T) {
+	cases := []struct {
+		changeFailureRate float64
+		want              string
+	}{
+		{0, "Elite"},
+		{0.2, "Medium"},
+		{0.5, "Low"},
+	}
+	for _, c := range cases {
+		if got := getChangeFailureRatePerformanceLevel(c.changeFailureRate); got != c.want {
+			t.Errorf("getChangeFailureRatePerformanceLevel(%v) = %v, want %v", c.changeFailureRate, got, c.want)
+		}
+	}
+}
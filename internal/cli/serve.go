@@ -0,0 +1,271 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hmiyado/four-keys/internal/cache"
+	"github.com/hmiyado/four-keys/internal/core"
+	"github.com/urfave/cli/v2"
+)
+
+// GetCommandServe returns the "serve" subcommand, which starts a long-running
+// HTTP server exposing /metrics, /releases and /fourkeys so a team can point
+// Grafana (or any scraper) at four-keys instead of re-running the CLI.
+func GetCommandServe() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "start a long-running HTTP server exposing four keys metrics",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "addr", Usage: "address to listen on", Value: ":8080"},
+			&cli.StringFlag{Name: "cache-dir", Usage: "directory for the on-disk release cache", Value: ".four-keys-cache"},
+			&cli.StringFlag{Name: "repos", Usage: "path to a JSON file listing repository URLs to pre-warm on startup"},
+			&cli.DurationFlag{Name: "refresh-interval", Usage: "how often to refresh pre-warmed repositories in the background", Value: 15 * time.Minute},
+		},
+		Action: serveAction,
+	}
+}
+
+type reposConfig struct {
+	Repositories []string `json:"repositories"`
+}
+
+func serveAction(ctx *cli.Context) error {
+	releaseCache, err := cache.New(ctx.String("cache-dir"))
+	if err != nil {
+		return err
+	}
+	server := &fourKeysServer{cache: releaseCache}
+
+	repos, err := loadReposConfig(ctx.String("repos"))
+	if err != nil {
+		return err
+	}
+	for _, repo := range repos {
+		go server.refresh(repo)
+	}
+	if refreshInterval := ctx.Duration("refresh-interval"); len(repos) > 0 && refreshInterval > 0 {
+		go server.refreshLoop(repos, refreshInterval)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", server.handleMetrics)
+	mux.HandleFunc("/releases", server.handleReleases)
+	mux.HandleFunc("/fourkeys", server.handleFourKeys)
+
+	addr := ctx.String("addr")
+	fmt.Fprintf(ctx.App.Writer, "four-keys serve listening on %v\n", addr)
+	return http.ListenAndServe(addr, mux)
+}
+
+func loadReposConfig(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read --repos config %v: %w", path, err)
+	}
+	var config reposConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("could not parse --repos config %v: %w", path, err)
+	}
+	return config.Repositories, nil
+}
+
+// fourKeysServer backs the serve command's HTTP handlers with a shared
+// on-disk cache keyed by (repoURL, lastCommitSHA, option), so repeated
+// queries over the same tag range skip re-walking commit history.
+type fourKeysServer struct {
+	cache *cache.Cache
+	mutex sync.Mutex
+}
+
+func (s *fourKeysServer) refreshLoop(repos []string, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		for _, repo := range repos {
+			s.refresh(repo)
+		}
+	}
+}
+
+func (s *fourKeysServer) refresh(repoURL string) {
+	if _, err := s.queryReleases(repoURL, &core.Option{}); err != nil {
+		fmt.Printf("four-keys serve: failed to refresh %v: %v\n", repoURL, err)
+	}
+}
+
+// queryReleases computes, or reuses a cached, QueryReleases result for
+// repoURL+option. The cache key includes the repository's current HEAD, so a
+// result is reused until HEAD moves; tag changes alone do not invalidate it.
+func (s *fourKeysServer) queryReleases(repoURL string, option *core.Option) ([]*core.Release, error) {
+	repository, err := core.OpenRepository(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	lastCommitSHA, err := core.LastCommitSHA(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	key := cache.Key{
+		RepoURL:       repoURL,
+		LastCommitSHA: lastCommitSHA,
+		Option:        optionCacheKey(option),
+	}
+
+	var cached []*core.Release
+	if hit, err := s.cache.Get(key, &cached); err == nil && hit {
+		return cached, nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	result := core.QueryReleases(repository, option)
+	if err := s.cache.Set(key, result); err != nil {
+		fmt.Printf("four-keys serve: failed to cache %v: %v\n", repoURL, err)
+	}
+	return result, nil
+}
+
+func optionCacheKey(option *core.Option) string {
+	return fmt.Sprintf("%v|%v", option.Since.Unix(), option.Until.Unix())
+}
+
+func (s *fourKeysServer) handleReleases(w http.ResponseWriter, r *http.Request) {
+	repo := r.URL.Query().Get("repo")
+	if repo == "" {
+		http.Error(w, "repo is required", http.StatusBadRequest)
+		return
+	}
+	option, err := optionFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	releaseList, err := s.queryReleases(repo, option)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJson(w, releaseList)
+}
+
+func (s *fourKeysServer) handleFourKeys(w http.ResponseWriter, r *http.Request) {
+	repo := r.URL.Query().Get("repo")
+	if repo == "" {
+		http.Error(w, "repo is required", http.StatusBadRequest)
+		return
+	}
+	option, err := optionFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	releaseList, err := s.queryReleases(repo, option)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJson(w, newDefaultCliOutput(releaseList, option))
+}
+
+func (s *fourKeysServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	repo := r.URL.Query().Get("repo")
+	if repo == "" {
+		http.Error(w, "repo is required", http.StatusBadRequest)
+		return
+	}
+	option, err := optionFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	releaseList, err := s.queryReleases(repo, option)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writePrometheus(&writerContext{w}, newDefaultCliOutput(releaseList, option))
+}
+
+// writerContext adapts an http.ResponseWriter to the subset of CliContextWrapper
+// that writePrometheus/writeTable/writeCSV need, so the serve command's HTTP
+// handlers can reuse the exact same rendering as the CLI's --format flag.
+type writerContext struct {
+	http.ResponseWriter
+}
+
+func (c *writerContext) Write(data []byte) {
+	c.ResponseWriter.Write(data)
+}
+
+func (c *writerContext) Error(err error) {
+	http.Error(c.ResponseWriter, err.Error(), http.StatusInternalServerError)
+}
+
+func optionFromQuery(r *http.Request) (*core.Option, error) {
+	query := r.URL.Query()
+	since, err := parseOptionalTime(query.Get("since"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid since: %w", err)
+	}
+	until, err := parseOptionalTime(query.Get("until"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid until: %w", err)
+	}
+	return &core.Option{Since: since, Until: until}, nil
+}
+
+func parseOptionalTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+func writeJson(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// newDefaultCliOutput computes the same DefaultCliOutput payload defaultAction
+// builds, for the serve command's HTTP handlers.
+func newDefaultCliOutput(releaseList []*core.Release, option *core.Option) *DefaultCliOutput {
+	duration := option.Until.Sub(option.Since)
+	daysCount := int(duration.Hours() / 24)
+	deploymentFrequency := float64(len(releaseList)) / float64(daysCount)
+	leadTimeForChanges := getMeanLeadTimeForChanges(releaseList)
+	timeToRestoreServices := getTimeToRestoreServices(releaseList)
+	changeFailureRate := getChangeFailureRate(releaseList)
+
+	return &DefaultCliOutput{
+		Option:                         option,
+		DeploymentFrequency:            deploymentFrequency,
+		LeadTimeForChanges:             getDurationWithTimeUnit(leadTimeForChanges),
+		LeadTimeForChangesPercentile:   getLeadTimeForChangesPercentiles(releaseList),
+		TimeToRestoreServices:          getDurationWithTimeUnit(timeToRestoreServices),
+		TimeToRestoreServicePercentile: getTimeToRestoreServicePercentiles(releaseList),
+		ChangeFailureRate:              changeFailureRate,
+		PerformanceLevels: DoraPerformanceLevels{
+			DeploymentFrequency:   getDeploymentFrequencyPerformanceLevel(deploymentFrequency),
+			LeadTimeForChanges:    getLeadTimeForChangesPerformanceLevel(leadTimeForChanges),
+			TimeToRestoreServices: getTimeToRestoreServicesPerformanceLevel(timeToRestoreServices),
+			ChangeFailureRate:     getChangeFailureRatePerformanceLevel(changeFailureRate),
+		},
+	}
+}
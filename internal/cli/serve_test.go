@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hmiyado/four-keys/internal/cache"
+	"github.com/hmiyado/four-keys/internal/core"
+	"github.com/urfave/cli/v2"
+)
+
+func TestLoadReposConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repos.json")
+	if err := os.WriteFile(path, []byte(`{"repositories":["https://github.com/a/a","https://github.com/b/b"]}`), 0o644); err != nil {
+		t.Fatalf("WriteFile() error: %v", err)
+	}
+
+	repos, err := loadReposConfig(path)
+	if err != nil {
+		t.Fatalf("loadReposConfig() error: %v", err)
+	}
+	if got, want := len(repos), 2; got != want {
+		t.Fatalf("loadReposConfig() returned %v repos, want %v", got, want)
+	}
+	if repos[0] != "https://github.com/a/a" || repos[1] != "https://github.com/b/b" {
+		t.Errorf("loadReposConfig() = %v, want [https://github.com/a/a https://github.com/b/b]", repos)
+	}
+}
+
+func TestLoadReposConfigEmptyPath(t *testing.T) {
+	repos, err := loadReposConfig("")
+	if err != nil {
+		t.Fatalf("loadReposConfig(\"\") error: %v", err)
+	}
+	if repos != nil {
+		t.Errorf("loadReposConfig(\"\") = %v, want nil", repos)
+	}
+}
+
+func TestLoadReposConfigMissingFile(t *testing.T) {
+	if _, err := loadReposConfig(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("loadReposConfig() with a missing file should return an error")
+	}
+}
+
+// newTaggedRepoFixture creates a local git repository with two tagged
+// commits, so queryReleases/the HTTP handlers can be exercised without a
+// network dependency on a real hosted repository.
+func newTaggedRepoFixture(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "test")
+	if err := os.WriteFile(filepath.Join(dir, "f.txt"), []byte("v1"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error: %v", err)
+	}
+	run("add", ".")
+	run("commit", "-q", "-m", "v1")
+	run("tag", "v1.0.0")
+	if err := os.WriteFile(filepath.Join(dir, "f.txt"), []byte("v2"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error: %v", err)
+	}
+	run("add", ".")
+	run("commit", "-q", "-m", "v2")
+	run("tag", "v2.0.0")
+	return dir
+}
+
+func newTestServer(t *testing.T) *fourKeysServer {
+	t.Helper()
+	releaseCache, err := cache.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("cache.New() error: %v", err)
+	}
+	return &fourKeysServer{cache: releaseCache}
+}
+
+func wideOpenOption() *core.Option {
+	return &core.Option{
+		Since: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestFourKeysServerQueryReleases(t *testing.T) {
+	repo := newTaggedRepoFixture(t)
+	cacheDir := t.TempDir()
+	releaseCache, err := cache.New(cacheDir)
+	if err != nil {
+		t.Fatalf("cache.New() error: %v", err)
+	}
+	server := &fourKeysServer{cache: releaseCache}
+
+	releases, err := server.queryReleases(repo, wideOpenOption())
+	if err != nil {
+		t.Fatalf("queryReleases() error: %v", err)
+	}
+	if got, want := len(releases), 2; got != want {
+		t.Fatalf("queryReleases() returned %v releases, want %v", got, want)
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		t.Fatalf("ReadDir() error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("queryReleases() wrote %v cache entries, want 1", len(entries))
+	}
+
+	cached, err := server.queryReleases(repo, wideOpenOption())
+	if err != nil {
+		t.Fatalf("queryReleases() (second call) error: %v", err)
+	}
+	if got, want := len(cached), len(releases); got != want {
+		t.Fatalf("queryReleases() (second call) returned %v releases, want %v", got, want)
+	}
+}
+
+func TestHandleReleasesRequiresRepo(t *testing.T) {
+	server := newTestServer(t)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/releases", nil)
+
+	server.handleReleases(w, r)
+
+	if w.Code != 400 {
+		t.Errorf("handleReleases() without repo = status %v, want 400", w.Code)
+	}
+}
+
+func TestHandleReleases(t *testing.T) {
+	repo := newTaggedRepoFixture(t)
+	server := newTestServer(t)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/releases?repo="+repo+"&since=2000-01-01&until=2100-01-01", nil)
+
+	server.handleReleases(w, r)
+
+	if w.Code != 200 {
+		t.Fatalf("handleReleases() = status %v, body %v", w.Code, w.Body.String())
+	}
+	if got, want := strings.Count(w.Body.String(), `"tag"`), 2; got != want {
+		t.Errorf("handleReleases() body has %v releases, want %v: %v", got, want, w.Body.String())
+	}
+}
+
+func TestHandleFourKeys(t *testing.T) {
+	repo := newTaggedRepoFixture(t)
+	server := newTestServer(t)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/fourkeys?repo="+repo+"&since=2000-01-01&until=2100-01-01", nil)
+
+	server.handleFourKeys(w, r)
+
+	if w.Code != 200 {
+		t.Fatalf("handleFourKeys() = status %v, body %v", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "deploymentFrequency") {
+		t.Errorf("handleFourKeys() body missing deploymentFrequency: %v", w.Body.String())
+	}
+}
+
+func TestHandleMetrics(t *testing.T) {
+	repo := newTaggedRepoFixture(t)
+	server := newTestServer(t)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/metrics?repo="+repo+"&since=2000-01-01&until=2100-01-01", nil)
+
+	server.handleMetrics(w, r)
+
+	if w.Code != 200 {
+		t.Fatalf("handleMetrics() = status %v, body %v", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "deployment_frequency") {
+		t.Errorf("handleMetrics() body missing deployment_frequency: %v", w.Body.String())
+	}
+}
+
+func TestServeActionReturnsErrorForInvalidReposConfig(t *testing.T) {
+	app := &cli.App{Commands: []*cli.Command{GetCommandServe()}}
+	err := app.Run([]string{
+		"four-keys", "serve",
+		"--cache-dir", t.TempDir(),
+		"--repos", filepath.Join(t.TempDir(), "missing.json"),
+	})
+	if err == nil {
+		t.Error("serveAction() with a missing --repos file should return an error before starting the HTTP server")
+	}
+}
@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hmiyado/four-keys/internal/core"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	BucketDay     = "day"
+	BucketWeek    = "week"
+	BucketMonth   = "month"
+	BucketQuarter = "quarter"
+)
+
+// SeriesPoint is the four keys metrics computed over a single bucket of time.
+type SeriesPoint struct {
+	BucketStart           time.Time             `json:"bucketStart"`
+	BucketEnd             time.Time             `json:"bucketEnd"`
+	DeploymentFrequency   float64               `json:"deploymentFrequency"`
+	LeadTimeForChanges    *DurationWithTimeUnit `json:"leadTimeForChanges"`
+	TimeToRestoreServices *DurationWithTimeUnit `json:"timeToRestoreServices"`
+	ChangeFailureRate     float64               `json:"changeFailureRate"`
+	ReleaseCount          int                   `json:"releaseCount"`
+}
+
+// SeriesOutput is the defaultAction output when --bucket is given: a time series
+// of the four keys instead of a single scalar snapshot.
+type SeriesOutput struct {
+	Option *core.Option  `json:"option"`
+	Series []SeriesPoint `json:"series"`
+}
+
+// bucketAction renders a windowed/rolling trend of the four keys instead of
+// the single-snapshot DefaultCliOutput, one SeriesPoint per bucket in
+// [option.Since, option.Until].
+func bucketAction(ctx *cli.Context, context *CliContextWrapper, allReleases []*core.Release, option *core.Option) error {
+	bucket := ctx.String("bucket")
+	rolling := ctx.Int("rolling")
+
+	series := computeSeries(allReleases, option.Since, option.Until, bucket)
+	if rolling > 1 {
+		series = applyRollingAverage(series, rolling)
+	}
+
+	outputJson, err := json.Marshal(&SeriesOutput{
+		Option: option,
+		Series: series,
+	})
+	if err != nil {
+		context.Error(err)
+		return err
+	}
+	context.Write(outputJson)
+	return nil
+}
+
+// computeSeries buckets releases by their Date into consecutive, non-overlapping
+// [bucketStart, bucketEnd) windows covering [since, until] and computes the four
+// keys within each bucket. Empty buckets are back-filled with zero DF/CFR and
+// nil durations. TimeToRestoreServices for a bucket is the mean of each
+// release's Result.TimeToRestore whose release falls in that bucket; since
+// TimeToRestore is already the duration from the originating failure to the
+// release that restored service, it naturally carries forward across bucket
+// boundaries without any extra bookkeeping here.
+func computeSeries(allReleases []*core.Release, since time.Time, until time.Time, bucket string) []SeriesPoint {
+	series := make([]SeriesPoint, 0)
+	releaseIndex := 0
+	for bucketStart := since; bucketStart.Before(until); bucketStart = nextBucketStart(bucketStart, bucket) {
+		bucketEnd := nextBucketStart(bucketStart, bucket)
+		if bucketEnd.After(until) {
+			bucketEnd = until
+		}
+
+		bucketReleases := make([]*core.Release, 0)
+		for releaseIndex < len(allReleases) && !allReleases[releaseIndex].Date.After(bucketEnd) {
+			if !allReleases[releaseIndex].Date.Before(bucketStart) {
+				bucketReleases = append(bucketReleases, allReleases[releaseIndex])
+			}
+			releaseIndex++
+		}
+
+		point := SeriesPoint{
+			BucketStart:  bucketStart,
+			BucketEnd:    bucketEnd,
+			ReleaseCount: len(bucketReleases),
+		}
+		if len(bucketReleases) == 0 {
+			series = append(series, point)
+			continue
+		}
+
+		bucketDays := bucketEnd.Sub(bucketStart).Hours() / 24
+		if bucketDays <= 0 {
+			bucketDays = 1
+		}
+		point.DeploymentFrequency = float64(len(bucketReleases)) / bucketDays
+		point.ChangeFailureRate = getChangeFailureRate(bucketReleases)
+		leadTime := getMeanLeadTimeForChanges(bucketReleases)
+		point.LeadTimeForChanges = durationPtr(getDurationWithTimeUnit(leadTime))
+		if timeToRestore := getMeanTimeToRestoreOfReleases(bucketReleases); timeToRestore != nil {
+			point.TimeToRestoreServices = durationPtr(getDurationWithTimeUnit(*timeToRestore))
+		}
+
+		series = append(series, point)
+	}
+	return series
+}
+
+func getMeanTimeToRestoreOfReleases(releaseSlice []*core.Release) *time.Duration {
+	sum := time.Duration(0)
+	count := 0
+	for _, release := range releaseSlice {
+		if release.Result.TimeToRestore != nil {
+			sum += *release.Result.TimeToRestore
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	mean := sum / time.Duration(count)
+	return &mean
+}
+
+func durationPtr(d DurationWithTimeUnit) *DurationWithTimeUnit {
+	return &d
+}
+
+func nextBucketStart(t time.Time, bucket string) time.Time {
+	switch bucket {
+	case BucketWeek:
+		return t.AddDate(0, 0, 7)
+	case BucketMonth:
+		return t.AddDate(0, 1, 0)
+	case BucketQuarter:
+		return t.AddDate(0, 3, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// applyRollingAverage replaces each bucket's metrics with the average over
+// itself and the preceding (rolling-1) buckets.
+func applyRollingAverage(series []SeriesPoint, rolling int) []SeriesPoint {
+	averaged := make([]SeriesPoint, len(series))
+	for i := range series {
+		windowStart := i - rolling + 1
+		if windowStart < 0 {
+			windowStart = 0
+		}
+		window := series[windowStart : i+1]
+
+		averaged[i] = series[i]
+		averaged[i].DeploymentFrequency = meanOfDeploymentFrequency(window)
+		averaged[i].ChangeFailureRate = meanOfChangeFailureRate(window)
+		averaged[i].LeadTimeForChanges = meanOfDurationField(window, func(p SeriesPoint) *DurationWithTimeUnit { return p.LeadTimeForChanges })
+		averaged[i].TimeToRestoreServices = meanOfDurationField(window, func(p SeriesPoint) *DurationWithTimeUnit { return p.TimeToRestoreServices })
+	}
+	return averaged
+}
+
+func meanOfDeploymentFrequency(window []SeriesPoint) float64 {
+	sum := 0.0
+	for _, point := range window {
+		sum += point.DeploymentFrequency
+	}
+	return sum / float64(len(window))
+}
+
+func meanOfChangeFailureRate(window []SeriesPoint) float64 {
+	sum := 0.0
+	for _, point := range window {
+		sum += point.ChangeFailureRate
+	}
+	return sum / float64(len(window))
+}
+
+func meanOfDurationField(window []SeriesPoint, field func(SeriesPoint) *DurationWithTimeUnit) *DurationWithTimeUnit {
+	sum := time.Duration(0)
+	count := 0
+	for _, point := range window {
+		if value := field(point); value != nil {
+			sum += time.Duration(*value)
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	mean := getDurationWithTimeUnit(sum / time.Duration(count))
+	return &mean
+}
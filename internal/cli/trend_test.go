@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hmiyado/four-keys/internal/core"
+)
+
+func TestComputeSeriesBucketsByDay(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	releases := []*core.Release{
+		{
+			Tag:                "v1",
+			Date:               time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			LeadTimeForChanges: time.Hour,
+			Result:             core.ReleaseResult{IsSuccess: true},
+		},
+		{
+			Tag:                "v2",
+			Date:               time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC),
+			LeadTimeForChanges: 2 * time.Hour,
+			Result:             core.ReleaseResult{IsSuccess: false},
+		},
+	}
+
+	series := computeSeries(releases, since, until, BucketDay)
+	if len(series) != 2 {
+		t.Fatalf("expected 2 buckets, got %v", len(series))
+	}
+
+	if series[0].ReleaseCount != 1 || series[0].ChangeFailureRate != 0 {
+		t.Errorf("bucket 0: got releaseCount=%v changeFailureRate=%v, want 1 and 0", series[0].ReleaseCount, series[0].ChangeFailureRate)
+	}
+	if series[1].ReleaseCount != 1 || series[1].ChangeFailureRate != 1 {
+		t.Errorf("bucket 1: got releaseCount=%v changeFailureRate=%v, want 1 and 1", series[1].ReleaseCount, series[1].ChangeFailureRate)
+	}
+}
+
+func TestComputeSeriesBackfillsEmptyBuckets(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)
+
+	releases := []*core.Release{
+		{
+			Tag:                "v1",
+			Date:               time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			LeadTimeForChanges: time.Hour,
+			Result:             core.ReleaseResult{IsSuccess: true},
+		},
+	}
+
+	series := computeSeries(releases, since, until, BucketDay)
+	if len(series) != 3 {
+		t.Fatalf("expected 3 buckets, got %v", len(series))
+	}
+	for i := 1; i < len(series); i++ {
+		if series[i].ReleaseCount != 0 || series[i].DeploymentFrequency != 0 || series[i].LeadTimeForChanges != nil {
+			t.Errorf("bucket %v should be back-filled empty, got %+v", i, series[i])
+		}
+	}
+}
+
+func TestApplyRollingAverage(t *testing.T) {
+	series := []SeriesPoint{
+		{DeploymentFrequency: 1},
+		{DeploymentFrequency: 3},
+		{DeploymentFrequency: 5},
+	}
+
+	averaged := applyRollingAverage(series, 2)
+	if averaged[0].DeploymentFrequency != 1 {
+		t.Errorf("bucket 0 rolling(2) = %v, want 1 (no preceding bucket yet)", averaged[0].DeploymentFrequency)
+	}
+	if averaged[1].DeploymentFrequency != 2 {
+		t.Errorf("bucket 1 rolling(2) = %v, want 2", averaged[1].DeploymentFrequency)
+	}
+	if averaged[2].DeploymentFrequency != 4 {
+		t.Errorf("bucket 2 rolling(2) = %v, want 4", averaged[2].DeploymentFrequency)
+	}
+}
@@ -0,0 +1,188 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	IncidentSourceGitHub = "github"
+	IncidentSourceGitLab = "gitlab"
+	IncidentSourceJira   = "jira"
+)
+
+// Incident is a real production incident, as reported by an issue tracker,
+// that overlaps a window of releases. An incident with ResolvedAt == nil is
+// still open.
+type Incident struct {
+	ID         string     `json:"id"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ResolvedAt *time.Time `json:"resolvedAt"`
+}
+
+// IsOpenAt reports whether the incident has not yet been resolved as of t.
+func (i *Incident) IsOpenAt(t time.Time) bool {
+	if t.Before(i.CreatedAt) {
+		return false
+	}
+	return i.ResolvedAt == nil || i.ResolvedAt.After(t)
+}
+
+// IncidentProvider fetches real production incidents from an issue tracker,
+// so ChangeFailureRate/TimeToRestoreServices can be tied to actual incidents
+// instead of a commit-message heuristic. QueryReleases marks a release
+// IsSuccess=false when its date falls inside any returned incident's
+// [CreatedAt, ResolvedAt) window, and derives TimeToRestore from that
+// incident's CreatedAt -> ResolvedAt instead of release -> release.
+type IncidentProvider interface {
+	Incidents(ctx context.Context, option *Option) ([]Incident, error)
+}
+
+// GitHubIncidentProvider fetches incidents from GitHub Issues labeled with Label
+// (e.g. "incident", "severity/sev1").
+type GitHubIncidentProvider struct {
+	Owner string
+	Repo  string
+	Label string
+	Token string
+}
+
+type gitHubIssue struct {
+	CreatedAt string `json:"created_at"`
+	ClosedAt  string `json:"closed_at"`
+}
+
+func (p *GitHubIncidentProvider) Incidents(ctx context.Context, option *Option) ([]Incident, error) {
+	url := "https://api.github.com/repos/" + p.Owner + "/" + p.Repo + "/issues?state=all&labels=" + p.Label
+	var issues []gitHubIssue
+	if err := getJson(ctx, url, p.Token, &issues); err != nil {
+		return nil, err
+	}
+
+	incidents := make([]Incident, 0, len(issues))
+	for i, issue := range issues {
+		incident, err := newIncidentFromTimestamps(formatIssueID("github", i), issue.CreatedAt, issue.ClosedAt)
+		if err != nil {
+			option.Debugln("github issue could not be parsed as an incident:", err)
+			continue
+		}
+		incidents = append(incidents, incident)
+	}
+	return incidents, nil
+}
+
+// GitLabIncidentProvider fetches incidents from GitLab Issues labeled with Label.
+type GitLabIncidentProvider struct {
+	ProjectID string
+	Label     string
+	Token     string
+}
+
+type gitLabIssue struct {
+	CreatedAt string `json:"created_at"`
+	ClosedAt  string `json:"closed_at"`
+}
+
+func (p *GitLabIncidentProvider) Incidents(ctx context.Context, option *Option) ([]Incident, error) {
+	url := "https://gitlab.com/api/v4/projects/" + p.ProjectID + "/issues?labels=" + p.Label
+	var issues []gitLabIssue
+	if err := getJson(ctx, url, p.Token, &issues); err != nil {
+		return nil, err
+	}
+
+	incidents := make([]Incident, 0, len(issues))
+	for i, issue := range issues {
+		incident, err := newIncidentFromTimestamps(formatIssueID("gitlab", i), issue.CreatedAt, issue.ClosedAt)
+		if err != nil {
+			option.Debugln("gitlab issue could not be parsed as an incident:", err)
+			continue
+		}
+		incidents = append(incidents, incident)
+	}
+	return incidents, nil
+}
+
+// JiraIncidentProvider fetches incidents from Jira issues matching JQL.
+type JiraIncidentProvider struct {
+	BaseURL string
+	JQL     string
+	Email   string
+	Token   string
+}
+
+type jiraSearchResult struct {
+	Issues []struct {
+		Fields struct {
+			Created  string `json:"created"`
+			Resolved string `json:"resolutiondate"`
+		} `json:"fields"`
+	} `json:"issues"`
+}
+
+func (p *JiraIncidentProvider) Incidents(ctx context.Context, option *Option) ([]Incident, error) {
+	url := p.BaseURL + "/rest/api/2/search?jql=" + p.JQL
+	var result jiraSearchResult
+	if err := getJson(ctx, url, p.Token, &result); err != nil {
+		return nil, err
+	}
+
+	incidents := make([]Incident, 0, len(result.Issues))
+	for i, issue := range result.Issues {
+		incident, err := newIncidentFromTimestamps(formatIssueID("jira", i), issue.Fields.Created, issue.Fields.Resolved)
+		if err != nil {
+			option.Debugln("jira issue could not be parsed as an incident:", err)
+			continue
+		}
+		incidents = append(incidents, incident)
+	}
+	return incidents, nil
+}
+
+func formatIssueID(source string, index int) string {
+	return fmt.Sprintf("%v-%v", source, index)
+}
+
+func newIncidentFromTimestamps(id string, createdAt string, resolvedAt string) (Incident, error) {
+	created, err := time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return Incident{}, err
+	}
+	incident := Incident{ID: id, CreatedAt: created}
+	if resolvedAt != "" {
+		resolved, err := time.Parse(time.RFC3339, resolvedAt)
+		if err == nil {
+			incident.ResolvedAt = &resolved
+		}
+	}
+	return incident, nil
+}
+
+// applyIncidents marks each release whose Date falls inside an incident's
+// [CreatedAt, ResolvedAt) window as failed and, for the earliest release dated
+// at or after ResolvedAt, computes TimeToRestore from the incident's
+// CreatedAt -> ResolvedAt. releases may be in any order. This runs
+// alongside the commit-regex heuristic already applied in QueryReleases: a
+// release found failed by either signal stays failed (union of signals).
+func applyIncidents(releases []*Release, incidents []Incident) {
+	for _, incident := range incidents {
+		restoredByIndex := -1
+		for i, release := range releases {
+			if release.Date.Before(incident.CreatedAt) {
+				continue
+			}
+			if incident.ResolvedAt != nil && !release.Date.Before(*incident.ResolvedAt) {
+				if restoredByIndex < 0 || release.Date.Before(releases[restoredByIndex].Date) {
+					restoredByIndex = i
+				}
+				continue
+			}
+			release.Result.IsSuccess = false
+		}
+		if restoredByIndex < 0 || incident.ResolvedAt == nil {
+			continue
+		}
+		timeToRestore := incident.ResolvedAt.Sub(incident.CreatedAt)
+		releases[restoredByIndex].Result.TimeToRestore = &timeToRestore
+	}
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApplyIncidentsMarksOverlappingReleasesFailedAndSetsTimeToRestore(t *testing.T) {
+	day := func(n int) time.Time { return time.Date(2024, 1, n, 0, 0, 0, 0, time.UTC) }
+
+	releases := []*Release{
+		{Tag: "v1", Date: day(1), Result: ReleaseResult{IsSuccess: true}},
+		{Tag: "v2", Date: day(3), Result: ReleaseResult{IsSuccess: true}},
+		{Tag: "v3", Date: day(5), Result: ReleaseResult{IsSuccess: true}},
+	}
+	resolvedAt := day(4)
+	incidents := []Incident{
+		{ID: "incident-1", CreatedAt: day(2), ResolvedAt: &resolvedAt},
+	}
+
+	applyIncidents(releases, incidents)
+
+	if releases[0].Result.IsSuccess != true {
+		t.Errorf("v1 (before the incident) should stay successful")
+	}
+	if releases[1].Result.IsSuccess != false {
+		t.Errorf("v2 (inside the incident window) should be marked failed")
+	}
+	if releases[2].Result.IsSuccess != true {
+		t.Errorf("v3 (after the incident resolved) should stay successful")
+	}
+	if releases[2].Result.TimeToRestore == nil {
+		t.Fatal("v3 should have TimeToRestore set from the incident's CreatedAt->ResolvedAt window")
+	}
+	if got, want := *releases[2].Result.TimeToRestore, resolvedAt.Sub(day(2)); got != want {
+		t.Errorf("TimeToRestore = %v, want %v", got, want)
+	}
+}
+
+func TestApplyIncidentsLeavesOpenIncidentUnresolved(t *testing.T) {
+	day := func(n int) time.Time { return time.Date(2024, 1, n, 0, 0, 0, 0, time.UTC) }
+
+	releases := []*Release{
+		{Tag: "v1", Date: day(1), Result: ReleaseResult{IsSuccess: true}},
+		{Tag: "v2", Date: day(3), Result: ReleaseResult{IsSuccess: true}},
+	}
+	incidents := []Incident{
+		{ID: "incident-1", CreatedAt: day(2), ResolvedAt: nil},
+	}
+
+	applyIncidents(releases, incidents)
+
+	if releases[1].Result.IsSuccess != false {
+		t.Errorf("v2 (inside the still-open incident window) should be marked failed")
+	}
+	if releases[1].Result.TimeToRestore != nil {
+		t.Errorf("an unresolved incident should not set TimeToRestore")
+	}
+}
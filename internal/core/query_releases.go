@@ -3,17 +3,44 @@ package core
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"os/exec"
 	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
+// ReleaseResult records whether a release succeeded, and if it didn't, how
+// long it took to restore service.
+type ReleaseResult struct {
+	IsSuccess     bool           `json:"isSuccess"`
+	TimeToRestore *time.Duration `json:"timeToRestore,omitempty"`
+}
+
+func (r ReleaseResult) String() string {
+	if r.TimeToRestore == nil {
+		return fmt.Sprintf("(IsSuccess=%v)", r.IsSuccess)
+	}
+	return fmt.Sprintf("(IsSuccess=%v, TimeToRestore=%v)", r.IsSuccess, *r.TimeToRestore)
+}
+
+func (r ReleaseResult) Equal(another ReleaseResult) bool {
+	if r.IsSuccess != another.IsSuccess {
+		return false
+	}
+	if (r.TimeToRestore == nil) != (another.TimeToRestore == nil) {
+		return false
+	}
+	return r.TimeToRestore == nil || *r.TimeToRestore == *another.TimeToRestore
+}
+
 type Release struct {
 	Tag                string        `json:"tag"`
 	Date               time.Time     `json:"date"`
@@ -29,9 +56,25 @@ type Option struct {
 	IgnorePattern     *regexp.Regexp `json:"-"`
 	FixCommitPattern  *regexp.Regexp `json:"-"`
 	IsLocalRepository bool           `json:"-"`
-	StartTimerFunc    func(string)   `json:"-"`
-	StopTimerFunc     func(string)   `json:"-"`
-	DebuglnFunc       func(...any)   `json:"-"`
+	// SourceProvider resolves which commits count as releases. When nil,
+	// QueryReleases falls back to the historical tag-based TagProvider.
+	SourceProvider ReleaseSourceProvider `json:"-"`
+	// IncidentProvider, when set, ties ChangeFailureRate/TimeToRestoreServices
+	// to real incidents from an issue tracker. It runs in addition to the
+	// commit-regex heuristic (isFixedCommit), not instead of it: a release
+	// failed by either signal stays failed.
+	IncidentProvider IncidentProvider `json:"-"`
+	// Concurrency bounds how many sources getReleaseMetricsConcurrently
+	// computes in parallel. Zero (the default) means runtime.NumCPU().
+	Concurrency    int          `json:"-"`
+	StartTimerFunc func(string) `json:"-"`
+	StopTimerFunc  func(string) `json:"-"`
+	DebuglnFunc    func(...any) `json:"-"`
+	// hookMutex serializes calls into StartTimerFunc/StopTimerFunc/DebuglnFunc.
+	// getReleaseMetricsConcurrently invokes these hooks from worker-pool
+	// goroutines, but callers (e.g. a profiler backed by a plain map) are only
+	// required to support being called from a single goroutine at a time.
+	hookMutex sync.Mutex
 }
 
 func (r *Release) String() string {
@@ -68,51 +111,67 @@ func (o *Option) isFixedCommit(commitMessage string) bool {
 }
 
 func (o *Option) StartTimer(key string) {
-	if o != nil && o.StartTimerFunc != nil {
-		o.StartTimerFunc(key)
+	if o == nil || o.StartTimerFunc == nil {
+		return
 	}
+	o.hookMutex.Lock()
+	defer o.hookMutex.Unlock()
+	o.StartTimerFunc(key)
 }
 
 func (o *Option) StopTimer(key string) {
-	if o != nil && o.StopTimerFunc != nil {
-		o.StopTimerFunc(key)
+	if o == nil || o.StopTimerFunc == nil {
+		return
 	}
+	o.hookMutex.Lock()
+	defer o.hookMutex.Unlock()
+	o.StopTimerFunc(key)
 }
 
 func (o *Option) Debugln(a ...any) {
-	if o != nil && o.DebuglnFunc != nil {
-		o.DebuglnFunc(a...)
+	if o == nil || o.DebuglnFunc == nil {
+		return
 	}
+	o.hookMutex.Lock()
+	defer o.hookMutex.Unlock()
+	o.DebuglnFunc(a...)
 }
 
 // QueryReleases returns Releases sorted by date (first item is the oldest and last item is the newest)
 func QueryReleases(repository *git.Repository, option *Option) []*Release {
 	option.StartTimer("QueryReleases")
 	defer option.StopTimer("QueryReleases")
-	option.StartTimer("QueryTags")
-	tags := QueryTags(repository)
-	option.StopTimer("QueryTags")
-	option.Debugln("Tags count:", len(tags))
-	sources := getReleaseSourcesFromTags(repository, tags)
+	option.StartTimer("QuerySources")
+	sources, err := getReleaseSourceProvider(option).Sources(context.Background(), repository, option)
+	option.StopTimer("QuerySources")
+	if err != nil {
+		option.Debugln("failed to query release sources:", err)
+		sources = []ReleaseSource{}
+	}
 	option.Debugln("Sources count:", len(sources))
 
-	releases := make([]*Release, 0)
-	nextSuccessReleaseIndex := -1
-	isRestored := false
+	relevantIndices := make([]int, 0, len(sources))
 	for i, source := range sources {
 		if option.shouldIgnore(source.tag.Name().Short()) {
 			option.Debugln("source[", i, "](", source.tag.Name().Short(), ") is ignored")
 			continue
 		}
-
 		if !option.isInTimeRange(source.commit.Committer.When) {
 			option.Debugln("source[", i, "](", source.tag.Name().Short(), ") is skipped for outof time range")
 			continue
 		}
+		relevantIndices = append(relevantIndices, i)
+	}
 
-		timerKeyReleaseMetrics := fmt.Sprintf("source[%v](%v)GetReleaseMetrics", i, source.tag.Name().Short())
-		option.StartTimer(timerKeyReleaseMetrics)
+	option.StartTimer("GetReleaseMetrics")
+	metrics := getReleaseMetricsConcurrently(sources, relevantIndices, option, repository)
+	option.StopTimer("GetReleaseMetrics")
 
+	releases := make([]*Release, 0, len(relevantIndices))
+	nextSuccessReleaseIndex := -1
+	isRestored := false
+	for position, i := range relevantIndices {
+		source := sources[i]
 		isSuccess := !isRestored
 		if isSuccess {
 			if len(releases) > 0 && !releases[len(releases)-1].Result.IsSuccess {
@@ -122,26 +181,111 @@ func QueryReleases(repository *git.Repository, option *Option) []*Release {
 			nextSuccessReleaseIndex = len(releases)
 		}
 
-		leadTimeForChanges := time.Duration(0)
-		if option != nil && option.IsLocalRepository {
-			isRestored, leadTimeForChanges = getIsRestoredAndLeadTimeForChangesByLocalGit(sources, i, option)
-		} else {
-			isRestored, leadTimeForChanges = getIsRestoredAndLeadTimeForChangesByGoGit(sources, i, option, repository)
-		}
-		option.StopTimer(timerKeyReleaseMetrics)
-
+		isRestored = metrics[position].isRestored
 		releases = append(releases, &Release{
 			Tag:                source.tag.Name().Short(),
 			Date:               source.commit.Committer.When,
-			LeadTimeForChanges: leadTimeForChanges,
+			LeadTimeForChanges: metrics[position].leadTimeForChanges,
 			Result: ReleaseResult{
 				IsSuccess: isSuccess,
 			},
 		})
 	}
+
+	if option != nil && option.IncidentProvider != nil {
+		option.StartTimer("QueryIncidents")
+		incidents, err := option.IncidentProvider.Incidents(context.Background(), option)
+		option.StopTimer("QueryIncidents")
+		if err != nil {
+			option.Debugln("failed to query incidents:", err)
+		} else {
+			option.Debugln("Incidents count:", len(incidents))
+			applyIncidents(releases, incidents)
+		}
+	}
+
 	return releases
 }
 
+func getReleaseSourceProvider(option *Option) ReleaseSourceProvider {
+	if option != nil && option.SourceProvider != nil {
+		return option.SourceProvider
+	}
+	return &TagProvider{}
+}
+
+type releaseMetrics struct {
+	isRestored         bool
+	leadTimeForChanges time.Duration
+}
+
+// getReleaseMetricsConcurrently computes getIsRestoredAndLeadTimeForChanges*
+// for each source in relevantIndices on a worker pool sized by
+// option.Concurrency (default runtime.NumCPU()), and returns the results in
+// the same order as relevantIndices so QueryReleases's sequential
+// restoration-linking pass stays deterministic. For the go-git path, workers
+// share the single *repository: go-git's Log/CommitObject are read-only and
+// safe for concurrent use. For the local-git path, each worker shells out to
+// `git log` independently, so there is no shared state to guard.
+func getReleaseMetricsConcurrently(sources []ReleaseSource, relevantIndices []int, option *Option, repository *git.Repository) []releaseMetrics {
+	metrics := make([]releaseMetrics, len(relevantIndices))
+
+	concurrency := getConcurrency(option)
+	if concurrency > len(relevantIndices) {
+		concurrency = len(relevantIndices)
+	}
+	if concurrency <= 1 {
+		for position, i := range relevantIndices {
+			metrics[position] = computeReleaseMetrics(sources, i, option, repository)
+		}
+		return metrics
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for w := 0; w < concurrency; w++ {
+		go func() {
+			defer wg.Done()
+			for position := range jobs {
+				metrics[position] = computeReleaseMetrics(sources, relevantIndices[position], option, repository)
+			}
+		}()
+	}
+	for position := range relevantIndices {
+		jobs <- position
+	}
+	close(jobs)
+	wg.Wait()
+
+	return metrics
+}
+
+func computeReleaseMetrics(sources []ReleaseSource, i int, option *Option, repository *git.Repository) releaseMetrics {
+	source := sources[i]
+	timerKeyReleaseMetrics := fmt.Sprintf("source[%v](%v)GetReleaseMetrics", i, source.tag.Name().Short())
+	option.StartTimer(timerKeyReleaseMetrics)
+	defer option.StopTimer(timerKeyReleaseMetrics)
+
+	var isRestored bool
+	var leadTimeForChanges time.Duration
+	if option != nil && option.IsLocalRepository {
+		isRestored, leadTimeForChanges = getIsRestoredAndLeadTimeForChangesByLocalGit(sources, i, option)
+	} else {
+		isRestored, leadTimeForChanges = getIsRestoredAndLeadTimeForChangesByGoGit(sources, i, option, repository)
+	}
+	return releaseMetrics{isRestored: isRestored, leadTimeForChanges: leadTimeForChanges}
+}
+
+// getConcurrency returns option.Concurrency, defaulting to runtime.NumCPU()
+// when unset.
+func getConcurrency(option *Option) int {
+	if option != nil && option.Concurrency > 0 {
+		return option.Concurrency
+	}
+	return runtime.NumCPU()
+}
+
 // getIsRestoredAndLeadTimeForChangesByLocalGit gets isRestored and leadTimeForChanges by using local git command.
 // Local git command is about 10 times faster than go-git.
 func getIsRestoredAndLeadTimeForChangesByLocalGit(
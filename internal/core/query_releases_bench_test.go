@@ -0,0 +1,27 @@
+package core
+
+import "testing"
+
+// BenchmarkQueryReleases measures the speedup the worker pool introduced in
+// getReleaseMetricsConcurrently gives on a repository with many tags, using
+// go-git/go-git itself as a representative large repository.
+func BenchmarkQueryReleases(b *testing.B) {
+	repository, err := OpenRepository("https://github.com/go-git/go-git")
+	if err != nil {
+		b.Fatalf("could not clone go-git/go-git: %v", err)
+	}
+
+	b.Run("concurrency=1", func(b *testing.B) {
+		option := &Option{Concurrency: 1}
+		for i := 0; i < b.N; i++ {
+			QueryReleases(repository, option)
+		}
+	})
+
+	b.Run("concurrency=default", func(b *testing.B) {
+		option := &Option{}
+		for i := 0; i < b.N; i++ {
+			QueryReleases(repository, option)
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestOptionHooksAreSafeForConcurrentUse exercises StartTimer/StopTimer/Debugln
+// from many goroutines at once, as getReleaseMetricsConcurrently's worker pool
+// does. A caller-supplied hook backed by a plain map (the common case) panics
+// with "concurrent map writes" if two goroutines call into it at the same
+// time; hookMutex must serialize these calls so that can't happen. Run with
+// `go test -race` to additionally confirm there's no data race on the map.
+func TestOptionHooksAreSafeForConcurrentUse(t *testing.T) {
+	calls := map[string]int{}
+	option := &Option{
+		StartTimerFunc: func(key string) { calls[key]++ },
+		StopTimerFunc:  func(key string) { calls[key]++ },
+		DebuglnFunc:    func(a ...any) { calls["debug"]++ },
+	}
+
+	var wg sync.WaitGroup
+	const goroutines = 50
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			option.StartTimer("key")
+			option.Debugln("working")
+			option.StopTimer("key")
+		}()
+	}
+	wg.Wait()
+
+	if got, want := calls["key"], goroutines*2; got != want {
+		t.Errorf("calls[key] = %v, want %v", got, want)
+	}
+	if got, want := calls["debug"], goroutines; got != want {
+		t.Errorf("calls[debug] = %v, want %v", got, want)
+	}
+}
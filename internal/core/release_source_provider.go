@@ -0,0 +1,214 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+const (
+	ReleaseSourceTags      = "tags"
+	ReleaseSourceGitHub    = "github"
+	ReleaseSourceGitLab    = "gitlab"
+	ReleaseSourceChangelog = "changelog"
+)
+
+// ReleaseSourceProvider resolves the ordered list of ReleaseSource a repository
+// should be measured against. TagProvider is the default, matching the
+// historical tag-based behavior of QueryReleases; the other implementations
+// let teams that don't push tags for every release still compute four-keys.
+type ReleaseSourceProvider interface {
+	Sources(ctx context.Context, repository *git.Repository, option *Option) ([]ReleaseSource, error)
+}
+
+// TagProvider derives ReleaseSources from git tags, exactly as QueryReleases
+// did before ReleaseSourceProvider was introduced.
+type TagProvider struct{}
+
+func (p *TagProvider) Sources(ctx context.Context, repository *git.Repository, option *Option) ([]ReleaseSource, error) {
+	tags := QueryTags(repository)
+	return getReleaseSourcesFromTags(repository, tags), nil
+}
+
+// GitHubReleasesProvider derives ReleaseSources from the GitHub Releases API
+// instead of tags, for repositories that publish releases without pushing a
+// tag for every one.
+type GitHubReleasesProvider struct {
+	// Owner/Repo identify the GitHub repository, e.g. "hmiyado/four-keys".
+	Owner string
+	Repo  string
+	Token string
+}
+
+type gitHubRelease struct {
+	TagName         string `json:"tag_name"`
+	TargetCommitish string `json:"target_commitish"`
+}
+
+func (p *GitHubReleasesProvider) Sources(ctx context.Context, repository *git.Repository, option *Option) ([]ReleaseSource, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%v/%v/releases", p.Owner, p.Repo)
+	var releases []gitHubRelease
+	if err := getJson(ctx, url, p.Token, &releases); err != nil {
+		return nil, err
+	}
+
+	sources := make([]ReleaseSource, 0, len(releases))
+	for _, release := range releases {
+		source, err := resolveReleaseSource(repository, release.TagName, release.TargetCommitish)
+		if err != nil {
+			option.Debugln("github release", release.TagName, "could not be resolved to a commit:", err)
+			continue
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+// GitLabReleasesProvider derives ReleaseSources from the GitLab Releases API.
+type GitLabReleasesProvider struct {
+	// ProjectID is the numeric or URL-encoded path GitLab project identifier.
+	ProjectID string
+	Token     string
+}
+
+type gitLabRelease struct {
+	TagName string `json:"tag_name"`
+	Commit  struct {
+		ID string `json:"id"`
+	} `json:"commit"`
+}
+
+func (p *GitLabReleasesProvider) Sources(ctx context.Context, repository *git.Repository, option *Option) ([]ReleaseSource, error) {
+	url := fmt.Sprintf("https://gitlab.com/api/v4/projects/%v/releases", p.ProjectID)
+	var releases []gitLabRelease
+	if err := getJson(ctx, url, p.Token, &releases); err != nil {
+		return nil, err
+	}
+
+	sources := make([]ReleaseSource, 0, len(releases))
+	for _, release := range releases {
+		source, err := resolveReleaseSource(repository, release.TagName, release.Commit.ID)
+		if err != nil {
+			option.Debugln("gitlab release", release.TagName, "could not be resolved to a commit:", err)
+			continue
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+// ChangelogProvider derives ReleaseSources from a Keep-a-Changelog style
+// CHANGELOG.md, matching each `## [version] - date` heading back to the
+// tagged commit for that version.
+type ChangelogProvider struct {
+	// Path is the path to CHANGELOG.md on the local filesystem; a relative path
+	// is resolved against the process's working directory.
+	Path string
+}
+
+var changelogHeadingPattern = regexp.MustCompile(`^##\s*\[([^\]]+)\]`)
+
+func (p *ChangelogProvider) Sources(ctx context.Context, repository *git.Repository, option *Option) ([]ReleaseSource, error) {
+	path := p.Path
+	if path == "" {
+		path = "CHANGELOG.md"
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open changelog %v: %w", path, err)
+	}
+	defer file.Close()
+
+	versions := make([]string, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		matches := changelogHeadingPattern.FindStringSubmatch(scanner.Text())
+		if matches == nil {
+			continue
+		}
+		versions = append(versions, matches[1])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	tags := QueryTags(repository)
+	tagsByVersion := make(map[string]*plumbing.Reference, len(tags))
+	for _, tag := range tags {
+		name := tag.Name().Short()
+		tagsByVersion[name] = tag
+		tagsByVersion[strings.TrimPrefix(name, "v")] = tag
+	}
+
+	sources := make([]ReleaseSource, 0, len(versions))
+	for _, version := range versions {
+		tag, ok := tagsByVersion[version]
+		if !ok {
+			option.Debugln("changelog version", version, "has no matching tag")
+			continue
+		}
+		commit, err := resolveTagCommit(repository, tag)
+		if err != nil {
+			option.Debugln("changelog version", version, "tag could not be resolved to a commit:", err)
+			continue
+		}
+		sources = append(sources, newReleaseSource(tag, commit))
+	}
+	return sources, nil
+}
+
+func newReleaseSource(tag *plumbing.Reference, commit *object.Commit) ReleaseSource {
+	return ReleaseSource{tag: tag, commit: commit}
+}
+
+// resolveReleaseSource looks up the commit a hosted release points at, trying
+// the release's own tag first and falling back to its target branch/commitish.
+func resolveReleaseSource(repository *git.Repository, tagName string, targetCommitish string) (ReleaseSource, error) {
+	if tagRef, err := repository.Tag(tagName); err == nil {
+		commit, err := resolveTagCommit(repository, tagRef)
+		if err == nil {
+			return newReleaseSource(tagRef, commit), nil
+		}
+	}
+
+	hash, err := repository.ResolveRevision(plumbing.Revision(targetCommitish))
+	if err != nil {
+		return ReleaseSource{}, fmt.Errorf("could not resolve release %v to a commit: %w", tagName, err)
+	}
+	commit, err := repository.CommitObject(*hash)
+	if err != nil {
+		return ReleaseSource{}, fmt.Errorf("could not resolve release %v to a commit: %w", tagName, err)
+	}
+	tagRef := plumbing.NewHashReference(plumbing.ReferenceName("refs/tags/"+tagName), *hash)
+	return newReleaseSource(tagRef, commit), nil
+}
+
+func getJson(ctx context.Context, url string, token string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	response, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%v returned status %v", url, response.StatusCode)
+	}
+	return json.NewDecoder(response.Body).Decode(target)
+}
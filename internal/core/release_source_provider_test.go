@@ -0,0 +1,29 @@
+package core
+
+import "testing"
+
+func TestChangelogHeadingPatternExtractsVersion(t *testing.T) {
+	cases := []struct {
+		line string
+		want string
+		ok   bool
+	}{
+		{"## [1.2.3] - 2024-01-01", "1.2.3", true},
+		{"## [v1.2.3]", "v1.2.3", true},
+		{"## [Unreleased]", "Unreleased", true},
+		{"### Added", "", false},
+		{"1.2.3", "", false},
+	}
+	for _, c := range cases {
+		matches := changelogHeadingPattern.FindStringSubmatch(c.line)
+		if !c.ok {
+			if matches != nil {
+				t.Errorf("FindStringSubmatch(%q) = %v, want no match", c.line, matches)
+			}
+			continue
+		}
+		if matches == nil || matches[1] != c.want {
+			t.Errorf("FindStringSubmatch(%q) = %v, want version %q", c.line, matches, c.want)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// OpenRepository clones repoURL into memory and returns it as a *git.Repository,
+// matching the existing assumption throughout this package that a repository
+// specified by URL is in-memory (see getIsRestoredAndLeadTimeForChangesByGoGit).
+func OpenRepository(repoURL string) (*git.Repository, error) {
+	repository, err := git.Clone(memory.NewStorage(), memfs.New(), &git.CloneOptions{
+		URL:  repoURL,
+		Tags: git.AllTags,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not clone %v: %w", repoURL, err)
+	}
+	return repository, nil
+}
+
+// LastCommitSHA returns the hash of repository's HEAD commit. It is used as
+// the cache-busting component of a cache.Key: a previously computed
+// QueryReleases result for the same Option is reused until HEAD changes, even
+// if tags were added or moved in the meantime.
+func LastCommitSHA(repository *git.Repository) (string, error) {
+	head, err := repository.Head()
+	if err != nil {
+		return "", fmt.Errorf("could not resolve HEAD: %w", err)
+	}
+	return head.Hash().String(), nil
+}
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// ReleaseSource pairs a tag reference with the commit it points at. It is the
+// unit every ReleaseSourceProvider resolves a release down to before
+// QueryReleases walks its history.
+type ReleaseSource struct {
+	tag    *plumbing.Reference
+	commit *object.Commit
+}
+
+// QueryTags returns repository's tags ordered newest commit first.
+// getIsRestoredAndLeadTimeForChanges* bounds each release's history by the
+// next entry in the slice (the previous release), so the order here has to
+// be newest-to-oldest for that boundary to land on the right commit.
+func QueryTags(repository *git.Repository) []*plumbing.Reference {
+	tagRefs, err := repository.Tags()
+	if err != nil {
+		return nil
+	}
+
+	type taggedCommit struct {
+		ref    *plumbing.Reference
+		commit *object.Commit
+	}
+	tagged := make([]taggedCommit, 0)
+	tagRefs.ForEach(func(ref *plumbing.Reference) error {
+		commit, err := resolveTagCommit(repository, ref)
+		if err != nil {
+			return nil
+		}
+		tagged = append(tagged, taggedCommit{ref: ref, commit: commit})
+		return nil
+	})
+
+	sort.Slice(tagged, func(i, j int) bool {
+		return tagged[i].commit.Committer.When.After(tagged[j].commit.Committer.When)
+	})
+
+	refs := make([]*plumbing.Reference, len(tagged))
+	for i, t := range tagged {
+		refs[i] = t.ref
+	}
+	return refs
+}
+
+// getReleaseSourcesFromTags resolves tags to the commits they point at,
+// dropping any tag that can't be resolved (e.g. it points at a dangling
+// object) rather than failing the whole query.
+func getReleaseSourcesFromTags(repository *git.Repository, tags []*plumbing.Reference) []ReleaseSource {
+	sources := make([]ReleaseSource, 0, len(tags))
+	for _, tag := range tags {
+		commit, err := resolveTagCommit(repository, tag)
+		if err != nil {
+			continue
+		}
+		sources = append(sources, newReleaseSource(tag, commit))
+	}
+	return sources
+}
+
+// resolveTagCommit resolves ref to the commit it points at. Lightweight tags
+// point directly at a commit; annotated tags point at a tag object that
+// itself points at a commit.
+func resolveTagCommit(repository *git.Repository, ref *plumbing.Reference) (*object.Commit, error) {
+	if commit, err := repository.CommitObject(ref.Hash()); err == nil {
+		return commit, nil
+	}
+	tagObject, err := repository.TagObject(ref.Hash())
+	if err != nil {
+		return nil, err
+	}
+	return tagObject.Commit()
+}
+
+// traverseCommits walks until's history, newest first, calling fn for every
+// commit strictly after since (exclusive). since may be nil, meaning walk
+// all the way back to the root commit.
+func traverseCommits(repository *git.Repository, since *object.Commit, until *object.Commit, fn func(*object.Commit) error) error {
+	if until == nil {
+		return nil
+	}
+	commitIter, err := repository.Log(&git.LogOptions{From: until.Hash})
+	if err != nil {
+		return err
+	}
+	return commitIter.ForEach(func(c *object.Commit) error {
+		if since != nil && c.Hash == since.Hash {
+			return storer.ErrStop
+		}
+		return fn(c)
+	})
+}